@@ -0,0 +1,15 @@
+// Package cache provides small, dependency-free caches for the upstream
+// geocoding and forecast responses fetched by the weather handlers.
+package cache
+
+import "time"
+
+// Cache is a byte-oriented, TTL-aware cache. Implementations are safe for
+// concurrent use.
+type Cache interface {
+	// Get returns the cached value for key and whether it is still fresh.
+	Get(key string) ([]byte, bool)
+	// Set stores val under key, expiring it after ttl. A ttl of zero means
+	// the entry never expires.
+	Set(key string, val []byte, ttl time.Duration)
+}
@@ -0,0 +1,93 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// errTooOld is returned internally by loadFromDisk when an entry's TTL has
+// elapsed; callers treat it the same as a cache miss.
+var errTooOld = errors.New("cache: entry too old")
+
+// diskEntry is the on-disk JSON envelope wrapping a cached value with the
+// time it was written, so freshness can be judged against ttl on load.
+type diskEntry struct {
+	StoredAt time.Time `json:"stored_at"`
+	TTL      time.Duration
+	Value    []byte `json:"value"`
+}
+
+// Disk is a Cache backed by one JSON file per key under dir. It survives
+// process restarts, which matters for the geocoding cache in particular
+// since coordinates are looked up infrequently but are expensive to lose.
+type Disk struct {
+	dir string
+}
+
+// NewDisk returns a disk-backed cache rooted at dir, creating it if
+// necessary.
+func NewDisk(dir string) (*Disk, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("cache: creating cache dir: %w", err)
+	}
+	return &Disk{dir: dir}, nil
+}
+
+func (d *Disk) Get(key string) ([]byte, bool) {
+	val, err := d.loadFromDisk(key)
+	if err != nil {
+		return nil, false
+	}
+	return val, true
+}
+
+func (d *Disk) Set(key string, val []byte, ttl time.Duration) {
+	// Errors are swallowed here, same as the Cache interface's Set: a disk
+	// write failure should degrade to "no cache", not fail the request.
+	_ = d.saveToDisk(key, val, ttl)
+}
+
+func (d *Disk) loadFromDisk(key string) ([]byte, error) {
+	raw, err := os.ReadFile(d.path(key))
+	if err != nil {
+		return nil, err
+	}
+
+	var e diskEntry
+	if err := json.Unmarshal(raw, &e); err != nil {
+		return nil, err
+	}
+
+	if e.TTL > 0 && time.Since(e.StoredAt) > e.TTL {
+		return nil, errTooOld
+	}
+
+	return e.Value, nil
+}
+
+func (d *Disk) saveToDisk(key string, val []byte, ttl time.Duration) error {
+	e := diskEntry{
+		StoredAt: time.Now(),
+		TTL:      ttl,
+		Value:    val,
+	}
+
+	raw, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(d.path(key), raw, 0o644)
+}
+
+// path derives a filesystem-safe filename for key so arbitrary cache keys
+// (city names, coordinates) never collide with path separators.
+func (d *Disk) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(d.dir, fmt.Sprintf("%x.json", sum))
+}
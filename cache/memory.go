@@ -0,0 +1,88 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// entry is the value stored in the LRU's linked list.
+type entry struct {
+	key       string
+	val       []byte
+	expiresAt time.Time // zero value means "never expires"
+}
+
+// LRU is an in-memory Cache with a bounded number of entries and per-entry
+// expiry. Eviction happens both on capacity overflow (least recently used)
+// and lazily on Get (expired entries are dropped when encountered).
+type LRU struct {
+	mu       sync.Mutex
+	capacity int
+	ll       *list.List
+	items    map[string]*list.Element
+}
+
+// NewLRU creates an in-memory cache holding at most capacity entries.
+func NewLRU(capacity int) *LRU {
+	return &LRU{
+		capacity: capacity,
+		ll:       list.New(),
+		items:    make(map[string]*list.Element),
+	}
+}
+
+func (c *LRU) Get(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+
+	e := el.Value.(*entry)
+	if !e.expiresAt.IsZero() && time.Now().After(e.expiresAt) {
+		c.removeElement(el)
+		return nil, false
+	}
+
+	c.ll.MoveToFront(el)
+	return e.val, true
+}
+
+func (c *LRU) Set(key string, val []byte, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var expiresAt time.Time
+	if ttl > 0 {
+		expiresAt = time.Now().Add(ttl)
+	}
+
+	if el, ok := c.items[key]; ok {
+		c.ll.MoveToFront(el)
+		el.Value.(*entry).val = val
+		el.Value.(*entry).expiresAt = expiresAt
+		return
+	}
+
+	el := c.ll.PushFront(&entry{key: key, val: val, expiresAt: expiresAt})
+	c.items[key] = el
+
+	if c.capacity > 0 && c.ll.Len() > c.capacity {
+		c.removeOldest()
+	}
+}
+
+func (c *LRU) removeOldest() {
+	el := c.ll.Back()
+	if el != nil {
+		c.removeElement(el)
+	}
+}
+
+func (c *LRU) removeElement(el *list.Element) {
+	c.ll.Remove(el)
+	delete(c.items, el.Value.(*entry).key)
+}
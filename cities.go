@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/luxeave/go-weather-forecast/provider"
+)
+
+// maxConcurrentCities bounds how many cities' geocode+forecast pipelines
+// run at once for a single request, so a `?city=` list with dozens of
+// entries can't exhaust upstream rate limits or local goroutines.
+const maxConcurrentCities = 4
+
+// fetchCityWeather resolves query and its forecast in a pipelined fashion,
+// similar to wwo's coordChan pattern: the geocode runs in a goroutine that
+// writes its result to a buffered channel as soon as it's ready, rather
+// than blocking the caller on a plain function call.
+func fetchCityWeather(ctx context.Context, db *sqlx.DB, p provider.Provider, query cityQuery, opts provider.ForecastOptions) (WeatherDisplay, error) {
+	type geocodeResult struct {
+		latlong *LatLong
+		err     error
+	}
+
+	coordChan := make(chan geocodeResult, 1)
+	go func() {
+		latlong, err := getLatLong(ctx, db, p, query)
+		coordChan <- geocodeResult{latlong, err}
+	}()
+
+	var resolved geocodeResult
+	select {
+	case resolved = <-coordChan:
+	case <-ctx.Done():
+		return WeatherDisplay{}, ctx.Err()
+	}
+	if resolved.err != nil {
+		return WeatherDisplay{}, resolved.err
+	}
+
+	loc := provider.LatLong{Latitude: resolved.latlong.Latitude, Longitude: resolved.latlong.Longitude}
+	forecast, err := cachedForecast(ctx, p, loc, opts)
+	if err != nil {
+		return WeatherDisplay{}, err
+	}
+
+	return extractWeatherData(query.Name, forecast, string(opts.Units))
+}
+
+// fetchCitiesWeather fans out fetchCityWeather across queries using a
+// bounded-concurrency errgroup, so `?city=Paris&city=Berlin&city=Tokyo`
+// resolves in parallel instead of one request at a time. Results keep
+// the order of queries. If the request context is canceled (e.g. the
+// client disconnects), outstanding upstream requests are aborted.
+func fetchCitiesWeather(ctx context.Context, db *sqlx.DB, p provider.Provider, queries []cityQuery, opts provider.ForecastOptions) ([]WeatherDisplay, error) {
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(maxConcurrentCities)
+
+	displays := make([]WeatherDisplay, len(queries))
+	for i, query := range queries {
+		i, query := i, query
+		g.Go(func() error {
+			display, err := fetchCityWeather(gctx, db, p, query, opts)
+			if err != nil {
+				return err
+			}
+			displays[i] = display
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	return displays, nil
+}
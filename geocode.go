@@ -0,0 +1,118 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/luxeave/go-weather-forecast/provider"
+)
+
+// countryAbbreviations expands common country abbreviations to the full
+// names Open-Meteo's geocoder returns, the way glance's openmeteo feed
+// does, so a request like `?country=US` matches a candidate whose
+// Country is "United States".
+var countryAbbreviations = map[string]string{
+	"US": "United States",
+	"UK": "United Kingdom",
+}
+
+func expandCountry(country string) string {
+	if expanded, ok := countryAbbreviations[strings.ToUpper(country)]; ok {
+		return expanded
+	}
+	return country
+}
+
+// usStateAbbreviations expands US state/territory postal abbreviations to
+// the full names Open-Meteo's geocoder returns as admin1, so a request like
+// `?city=Springfield,IL,US` matches a candidate whose Admin1 is "Illinois".
+var usStateAbbreviations = map[string]string{
+	"AL": "Alabama", "AK": "Alaska", "AZ": "Arizona", "AR": "Arkansas",
+	"CA": "California", "CO": "Colorado", "CT": "Connecticut", "DE": "Delaware",
+	"FL": "Florida", "GA": "Georgia", "HI": "Hawaii", "ID": "Idaho",
+	"IL": "Illinois", "IN": "Indiana", "IA": "Iowa", "KS": "Kansas",
+	"KY": "Kentucky", "LA": "Louisiana", "ME": "Maine", "MD": "Maryland",
+	"MA": "Massachusetts", "MI": "Michigan", "MN": "Minnesota", "MS": "Mississippi",
+	"MO": "Missouri", "MT": "Montana", "NE": "Nebraska", "NV": "Nevada",
+	"NH": "New Hampshire", "NJ": "New Jersey", "NM": "New Mexico", "NY": "New York",
+	"NC": "North Carolina", "ND": "North Dakota", "OH": "Ohio", "OK": "Oklahoma",
+	"OR": "Oregon", "PA": "Pennsylvania", "RI": "Rhode Island", "SC": "South Carolina",
+	"SD": "South Dakota", "TN": "Tennessee", "TX": "Texas", "UT": "Utah",
+	"VT": "Vermont", "VA": "Virginia", "WA": "Washington", "WV": "West Virginia",
+	"WI": "Wisconsin", "WY": "Wyoming", "DC": "District of Columbia",
+}
+
+func expandAdmin1(admin1 string) string {
+	if expanded, ok := usStateAbbreviations[strings.ToUpper(admin1)]; ok {
+		return expanded
+	}
+	return admin1
+}
+
+// cityQuery is a parsed `?city=` value: a bare name, optionally followed by
+// ",<admin1>,<country>" (e.g. "Springfield,IL,US") to disambiguate
+// same-named places up front.
+type cityQuery struct {
+	Name    string
+	Admin1  string
+	Country string
+}
+
+// parseCityQuery splits a "Springfield,IL,US"-style value into its parts.
+// Anything not supplied this way can still be passed as separate
+// `admin1`/`country` query parameters.
+func parseCityQuery(raw string) cityQuery {
+	parts := strings.Split(raw, ",")
+	q := cityQuery{Name: strings.TrimSpace(parts[0])}
+	if len(parts) > 1 {
+		q.Admin1 = strings.TrimSpace(parts[1])
+	}
+	if len(parts) > 2 {
+		q.Country = strings.TrimSpace(parts[2])
+	}
+	return q
+}
+
+// selectCandidate filters candidates by admin1/country (when provided) and
+// returns the best match, falling back to the first candidate overall if
+// nothing matches the filter exactly.
+func selectCandidate(candidates []provider.GeocodeCandidate, admin1, country string) provider.GeocodeCandidate {
+	if admin1 == "" && country == "" {
+		return candidates[0]
+	}
+
+	admin1 = expandAdmin1(admin1)
+	country = expandCountry(country)
+	for _, c := range candidates {
+		if admin1 != "" && !strings.EqualFold(c.Admin1, admin1) {
+			continue
+		}
+		if country != "" && !strings.EqualFold(c.Country, country) {
+			continue
+		}
+		return c
+	}
+
+	return candidates[0]
+}
+
+// selectLatLong is selectCandidate's counterpart for rows already
+// persisted in the `cities` table.
+func selectLatLong(rows []LatLong, admin1, country string) *LatLong {
+	if admin1 == "" && country == "" {
+		return &rows[0]
+	}
+
+	admin1 = expandAdmin1(admin1)
+	country = expandCountry(country)
+	for i, r := range rows {
+		if admin1 != "" && !strings.EqualFold(r.Admin1, admin1) {
+			continue
+		}
+		if country != "" && !strings.EqualFold(r.Country, country) {
+			continue
+		}
+		return &rows[i]
+	}
+
+	return &rows[0]
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"context"
+	"log"
+	"net"
+	"os"
+
+	"github.com/jmoiron/sqlx"
+	"google.golang.org/grpc"
+
+	// weatherpb (proto/*.pb.go) is generated by `make proto` and not
+	// committed; run it (or `make build`/`make test`) before this
+	// package will compile.
+	weatherpb "github.com/luxeave/go-weather-forecast/proto"
+	"github.com/luxeave/go-weather-forecast/provider"
+)
+
+// grpcServer implements weatherpb.WeatherServiceServer on top of the same
+// getLatLong/cachedForecast helpers the HTTP handlers use, so both
+// transports share caching, DB persistence, and provider selection.
+type grpcServer struct {
+	weatherpb.UnimplementedWeatherServiceServer
+
+	db       *sqlx.DB
+	provider provider.Provider
+}
+
+func (s *grpcServer) Geocode(ctx context.Context, req *weatherpb.RequestLocation) (*weatherpb.SendLocation, error) {
+	latlong, err := getLatLong(ctx, s.db, s.provider, cityQuery{Name: req.City})
+	if err != nil {
+		return nil, err
+	}
+	return &weatherpb.SendLocation{Latitude: latlong.Latitude, Longitude: latlong.Longitude}, nil
+}
+
+func (s *grpcServer) Current(ctx context.Context, req *weatherpb.RequestCurrent) (*weatherpb.SendCurrent, error) {
+	latlong, err := getLatLong(ctx, s.db, s.provider, cityQuery{Name: req.City})
+	if err != nil {
+		return nil, err
+	}
+
+	opts := provider.ForecastOptions{Days: 1, Units: unitsFromProto(req.Units)}
+	forecast, err := cachedForecast(ctx, s.provider, provider.LatLong{Latitude: latlong.Latitude, Longitude: latlong.Longitude}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	cond := provider.Describe(forecast.Current.WeatherCode)
+	return &weatherpb.SendCurrent{
+		Temperature: fromCelsiusUnits(forecast.Current.TemperatureC, opts.Units),
+		WeatherCode: int32(forecast.Current.WeatherCode),
+		Description: cond.Description,
+	}, nil
+}
+
+func (s *grpcServer) FiveDay(req *weatherpb.RequestFiveDay, stream weatherpb.WeatherService_FiveDayServer) error {
+	ctx := stream.Context()
+
+	latlong, err := getLatLong(ctx, s.db, s.provider, cityQuery{Name: req.City})
+	if err != nil {
+		return err
+	}
+
+	opts := provider.ForecastOptions{Days: 5, Units: unitsFromProto(req.Units)}
+	forecast, err := cachedForecast(ctx, s.provider, provider.LatLong{Latitude: latlong.Latitude, Longitude: latlong.Longitude}, opts)
+	if err != nil {
+		return err
+	}
+
+	for _, day := range forecast.Daily {
+		cond := provider.Describe(day.WeatherCode)
+		err := stream.Send(&weatherpb.SendDay{
+			Date:            day.Date.Format("2006-01-02"),
+			High:            fromCelsiusUnits(day.HighC, opts.Units),
+			Low:             fromCelsiusUnits(day.LowC, opts.Units),
+			WeatherCode:     int32(day.WeatherCode),
+			Description:     cond.Description,
+			PrecipitationMm: day.PrecipitationMM,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// fromCelsiusUnits converts a Celsius temperature to the unit system
+// requested over gRPC. It mirrors the HTTP path's fromCelsius but also
+// covers provider.UnitsStandard (Kelvin), which the HTTP API never
+// requests but the proto Units enum allows.
+func fromCelsiusUnits(c float64, units provider.Units) float64 {
+	switch units {
+	case provider.UnitsImperial:
+		return c*9/5 + 32
+	case provider.UnitsStandard:
+		return c + 273.15
+	default:
+		return c
+	}
+}
+
+func unitsFromProto(u weatherpb.Units) provider.Units {
+	switch u {
+	case weatherpb.Units_UNITS_IMPERIAL:
+		return provider.UnitsImperial
+	case weatherpb.Units_UNITS_STANDARD:
+		return provider.UnitsStandard
+	default:
+		return provider.UnitsMetric
+	}
+}
+
+// runGRPCServer starts the WeatherService gRPC server on GRPC_ADDR (or
+// :50051 if unset) and blocks, the same way gin's r.Run() does for HTTP; it
+// is meant to be run in its own goroutine alongside the Gin router.
+func runGRPCServer(db *sqlx.DB, weatherProvider provider.Provider) {
+	addr := os.Getenv("GRPC_ADDR")
+	if addr == "" {
+		addr = ":50051"
+	}
+
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		log.Fatalf("grpc: failed to listen on %s: %v", addr, err)
+	}
+
+	srv := grpc.NewServer()
+	weatherpb.RegisterWeatherServiceServer(srv, &grpcServer{db: db, provider: weatherProvider})
+
+	log.Printf("grpc: WeatherService listening on %s", addr)
+	if err := srv.Serve(lis); err != nil {
+		log.Fatalf("grpc: serve failed: %v", err)
+	}
+}
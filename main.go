@@ -1,45 +1,47 @@
 package main
 
 import (
-	"encoding/json"
-	"errors"
+	"context"
 	"fmt"
-	"io"
+	"math"
 	"net/http"
-	"net/url"
 	"os"
-	"time"
+	"strconv"
 
 	_ "github.com/lib/pq"
 
 	"github.com/gin-gonic/gin"
 	"github.com/jmoiron/sqlx"
-)
 
-type GeoResponse struct {
-	// A list of results; we only need the first one
-	Results []LatLong `json:"results"`
-}
+	"github.com/luxeave/go-weather-forecast/provider"
+	"github.com/luxeave/go-weather-forecast/provider/factory"
+)
 
 type LatLong struct {
-	Latitude  float64 `json:"latitude"`
-	Longitude float64 `json:"longitude"`
+	Latitude  float64 `json:"latitude" db:"lat"`
+	Longitude float64 `json:"longitude" db:"long"`
+	Admin1    string  `json:"admin1,omitempty" db:"admin1"`
+	Country   string  `json:"country,omitempty" db:"country"`
+	Timezone  string  `json:"timezone,omitempty" db:"timezone"`
 }
 
-type WeatherData struct{}
-type WeatherResponse struct {
-	Latitude  float64 `json:"latitude"`
-	Longitude float64 `json:"longitude"`
-	Timezone  string  `json:"timezone"`
-	Hourly    struct {
-		Time          []string  `json:"time"`
-		Temperature2m []float64 `json:"temperature_2m"`
-	} `json:"hourly"`
+// WeatherPage is what the weather.html template renders: one or more
+// cities' forecasts, fetched concurrently by fetchCitiesWeather.
+type WeatherPage struct {
+	Cities []WeatherDisplay
 }
 
 type WeatherDisplay struct {
 	City      string
+	Current   CurrentConditions
 	Forecasts []Forecast
+	Days      []DayForecast
+}
+
+type CurrentConditions struct {
+	Temperature string
+	Description string
+	Icon        string
 }
 
 type Forecast struct {
@@ -47,89 +49,164 @@ type Forecast struct {
 	Temperature string
 }
 
-func extractWeatherData(city string, rawWeather string) (WeatherDisplay, error) {
-	var weatherResponse WeatherResponse
-	if err := json.Unmarshal([]byte(rawWeather), &weatherResponse); err != nil {
-		return WeatherDisplay{}, fmt.Errorf("error decoding weather response: %w", err)
-	}
+// DayForecast is a single per-day card: high/low, the dominant weather
+// condition for that day, a precipitation bar, and sunrise/sunset in the
+// location's own timezone.
+type DayForecast struct {
+	Date             string
+	High             string
+	Low              string
+	Description      string
+	Icon             string
+	PrecipitationMM  string
+	Sunrise          string
+	Sunset           string
+	TemperatureScale float64 // 0..1, (temp-globalMin)/(globalMax-globalMin), for proportional bars
+	PercentWidth     int     // TemperatureScale*100, for the template's bar width
+}
+
+// extractWeatherData turns a provider-agnostic Forecast into the strings
+// and numbers the templates render, converting Celsius to the requested
+// display unit along the way.
+func extractWeatherData(city string, f provider.Forecast, units string) (WeatherDisplay, error) {
+	unitSymbol := unitSymbolFor(units)
 
 	var forecasts []Forecast
-	for i, t := range weatherResponse.Hourly.Time {
-		date, err := time.Parse("2006-01-02T15:04", t)
-		if err != nil {
-			return WeatherDisplay{}, err
-		}
-		forecast := Forecast{
-			Date:        date.Format("Mon 15:04"),
-			Temperature: fmt.Sprintf("%.1f°C", weatherResponse.Hourly.Temperature2m[i]),
-		}
-		forecasts = append(forecasts, forecast)
+	for _, h := range f.Hourly {
+		forecasts = append(forecasts, Forecast{
+			Date:        h.Time.Format("Mon 15:04"),
+			Temperature: fmt.Sprintf("%.1f°%s", fromCelsius(h.TemperatureC, units), unitSymbol),
+		})
 	}
+
+	currentCond := provider.Describe(f.Current.WeatherCode)
+	current := CurrentConditions{
+		Temperature: fmt.Sprintf("%.1f°%s", fromCelsius(f.Current.TemperatureC, units), unitSymbol),
+		Description: currentCond.Description,
+		Icon:        currentCond.Icon,
+	}
+
 	return WeatherDisplay{
 		City:      city,
+		Current:   current,
 		Forecasts: forecasts,
+		Days:      buildDayForecasts(f.Daily, units),
 	}, nil
 }
 
-func fetchLatLong(city string) (*LatLong, error) {
-	endpoint := fmt.Sprintf("https://geocoding-api.open-meteo.com/v1/search?name=%s&count=1&language=en&format=json", url.QueryEscape(city))
-	resp, err := http.Get(endpoint)
-	if err != nil {
-		return nil, fmt.Errorf("error making request to Geo API: %w", err)
+// buildDayForecasts converts the provider's already day-aggregated Daily
+// slice into display-ready cards, scaling each day's high temperature
+// against the whole forecast's range for the proportional bar, the same
+// technique glance's weatherColumn.Scale uses for its temperature graph.
+func buildDayForecasts(daily []provider.Day, units string) []DayForecast {
+	if len(daily) == 0 {
+		return nil
 	}
-	defer resp.Body.Close()
+	unitSymbol := unitSymbolFor(units)
 
-	var response GeoResponse
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return nil, fmt.Errorf("error decoding response: %w", err)
+	globalMin := math.Inf(1)
+	globalMax := math.Inf(-1)
+	for _, d := range daily {
+		globalMin = math.Min(globalMin, d.LowC)
+		globalMax = math.Max(globalMax, d.HighC)
 	}
 
-	// response.Results is a list of results, but we only need the first one
-	if len(response.Results) < 1 {
-		return nil, errors.New("no results found")
+	days := make([]DayForecast, 0, len(daily))
+	for _, d := range daily {
+		cond := provider.Describe(d.WeatherCode)
+		day := DayForecast{
+			Date:             d.Date.Format("Mon Jan 2"),
+			High:             fmt.Sprintf("%.0f°%s", fromCelsius(d.HighC, units), unitSymbol),
+			Low:              fmt.Sprintf("%.0f°%s", fromCelsius(d.LowC, units), unitSymbol),
+			Description:      cond.Description,
+			Icon:             cond.Icon,
+			PrecipitationMM:  fmt.Sprintf("%.1f mm", d.PrecipitationMM),
+			TemperatureScale: scaleTemperature(d.HighC, globalMin, globalMax),
+		}
+		day.PercentWidth = int(day.TemperatureScale * 100)
+		if !d.Sunrise.IsZero() {
+			day.Sunrise = d.Sunrise.Format("15:04")
+		}
+		if !d.Sunset.IsZero() {
+			day.Sunset = d.Sunset.Format("15:04")
+		}
+		days = append(days, day)
 	}
 
-	return &response.Results[0], nil
+	return days
 }
 
-func getLatLong(db *sqlx.DB, name string) (*LatLong, error) {
-	var latLong *LatLong
-	err := db.Get(&latLong, "SELECT lat, long FROM cities WHERE name = $1", name)
-	if err == nil {
-		return latLong, nil
+// scaleTemperature clamps (temp-min)/(max-min) to [0,1] so templates can
+// render a proportional bar.
+func scaleTemperature(temp, min, max float64) float64 {
+	if max <= min {
+		return 0
 	}
-
-	latLong, err = fetchLatLong(name)
-	if err != nil {
-		return nil, err
+	scale := (temp - min) / (max - min)
+	if scale < 0 {
+		return 0
 	}
+	if scale > 1 {
+		return 1
+	}
+	return scale
+}
 
-	err = insertCity(db, name, *latLong)
-	if err != nil {
-		return nil, err
+// unitSymbolFor returns the degree-symbol suffix for a "metric"/"imperial"
+// display unit.
+func unitSymbolFor(units string) string {
+	if units == "imperial" {
+		return "F"
 	}
+	return "C"
+}
 
-	return latLong, nil
+// fromCelsius converts a Celsius temperature (the provider package's
+// internal normalization) to the requested display unit.
+func fromCelsius(c float64, units string) float64 {
+	if units == "imperial" {
+		return c*9/5 + 32
+	}
+	return c
 }
 
-func getWeather(latLong LatLong) (string, error) {
-	endpoint := fmt.Sprintf("https://api.open-meteo.com/v1/forecast?latitude=%.6f&longitude=%.6f&hourly=temperature_2m", latLong.Latitude, latLong.Longitude)
-	resp, err := http.Get(endpoint)
-	if err != nil {
-		return "", fmt.Errorf("error making request to Weather API: %w", err)
+// getLatLong resolves query to a coordinate, preferring a previously
+// disambiguated row in the `cities` table over a fresh geocode. When a
+// bare city name matches more than one stored row (e.g. several
+// "Springfield"s resolved for different users), query's Admin1/Country
+// narrow it down the same way they narrow a fresh geocode.
+func getLatLong(ctx context.Context, db *sqlx.DB, weatherProvider provider.Provider, query cityQuery) (*LatLong, error) {
+	var rows []LatLong
+	if err := db.Select(&rows, "SELECT lat, long, admin1, country, timezone FROM cities WHERE name = $1", query.Name); err == nil && len(rows) > 0 {
+		return selectLatLong(rows, query.Admin1, query.Country), nil
 	}
-	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
+	candidates, err := cachedGeocode(ctx, weatherProvider, query.Name)
 	if err != nil {
-		return "", fmt.Errorf("error reading response body: %w", err)
+		return nil, err
+	}
+	chosen := selectCandidate(candidates, query.Admin1, query.Country)
+
+	latLong := &LatLong{
+		Latitude:  chosen.Latitude,
+		Longitude: chosen.Longitude,
+		Admin1:    chosen.Admin1,
+		Country:   chosen.Country,
+		Timezone:  chosen.Timezone,
+	}
+
+	if err := insertCity(db, query.Name, *latLong); err != nil {
+		return nil, err
 	}
 
-	return string(body), nil
+	return latLong, nil
 }
 
 func insertCity(db *sqlx.DB, name string, latLong LatLong) error {
-	_, err := db.Exec("INSERT INTO cities (name, lat, long) VALUES ($1, $2, $3)", name, latLong.Latitude, latLong.Longitude)
+	_, err := db.Exec(
+		"INSERT INTO cities (name, lat, long, admin1, country, timezone) VALUES ($1, $2, $3, $4, $5, $6)",
+		name, latLong.Latitude, latLong.Longitude, latLong.Admin1, latLong.Country, latLong.Timezone,
+	)
 	return err
 }
 
@@ -139,31 +216,70 @@ func main() {
 
 	db := sqlx.MustConnect("postgres", os.Getenv("DATABASE_URL"))
 
+	weatherProvider, err := factory.New(os.Getenv("WEATHER_PROVIDER"))
+	if err != nil {
+		panic(err)
+	}
+
+	go runGRPCServer(db, weatherProvider)
+
 	r.GET("/weather", func(c *gin.Context) {
-		city := c.Query("city")
-		latlong, err := getLatLong(db, city)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		ctx := c.Request.Context()
+
+		cities := c.QueryArray("city")
+		if len(cities) == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "city is required"})
+			return
+		}
+
+		days, err := strconv.Atoi(c.DefaultQuery("days", "7"))
+		if err != nil || days < 1 || days > 16 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "days must be an integer between 1 and 16"})
+			return
+		}
+
+		units := c.DefaultQuery("units", "metric")
+		if units != "metric" && units != "imperial" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "units must be 'metric' or 'imperial'"})
 			return
 		}
 
-		weather, err := getWeather(*latlong)
+		queries := make([]cityQuery, len(cities))
+		for i, city := range cities {
+			q := parseCityQuery(city)
+			if q.Admin1 == "" {
+				q.Admin1 = c.Query("admin1")
+			}
+			if q.Country == "" {
+				q.Country = c.Query("country")
+			}
+			queries[i] = q
+		}
+
+		opts := provider.ForecastOptions{Days: days, Units: provider.Units(units)}
+		displays, err := fetchCitiesWeather(ctx, db, weatherProvider, queries, opts)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 			return
 		}
 
-		// print out weather
-		fmt.Println(weather)
+		c.HTML(http.StatusOK, "weather.html", WeatherPage{Cities: displays})
+	})
 
-		//////// NEW CODE STARTS HERE ////////
-		weatherDisplay, err := extractWeatherData(city, weather)
+	r.GET("/geocode", func(c *gin.Context) {
+		q := c.Query("q")
+		if q == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "q is required"})
+			return
+		}
+
+		candidates, err := weatherProvider.Geocode(c.Request.Context(), q, 10)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 			return
 		}
-		c.HTML(http.StatusOK, "weather.html", weatherDisplay)
-		//////////////////////////////////////
+
+		c.JSON(http.StatusOK, candidates)
 	})
 
 	r.GET("/", func(c *gin.Context) {
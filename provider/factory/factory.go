@@ -0,0 +1,40 @@
+// Package factory constructs a provider.Provider by name. It lives outside
+// package provider because it imports each concrete backend
+// (openmeteo/openweathermap/wwo), which in turn import provider itself;
+// keeping New() in package provider would create an import cycle.
+package factory
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/luxeave/go-weather-forecast/provider"
+	"github.com/luxeave/go-weather-forecast/provider/openmeteo"
+	"github.com/luxeave/go-weather-forecast/provider/openweathermap"
+	"github.com/luxeave/go-weather-forecast/provider/wwo"
+)
+
+// New constructs the Provider named by name ("openmeteo", "openweathermap",
+// or "wwo"), reading any required API key from its conventional
+// environment variable. An empty name defaults to "openmeteo", which needs
+// no key.
+func New(name string) (provider.Provider, error) {
+	switch name {
+	case "", "openmeteo":
+		return openmeteo.New(), nil
+	case "openweathermap":
+		key := os.Getenv("OPENWEATHERMAP_API_KEY")
+		if key == "" {
+			return nil, fmt.Errorf("factory: OPENWEATHERMAP_API_KEY is required for the openweathermap provider")
+		}
+		return openweathermap.New(key), nil
+	case "wwo":
+		key := os.Getenv("WWO_API_KEY")
+		if key == "" {
+			return nil, fmt.Errorf("factory: WWO_API_KEY is required for the wwo provider")
+		}
+		return wwo.New(key), nil
+	default:
+		return nil, fmt.Errorf("factory: unknown WEATHER_PROVIDER %q", name)
+	}
+}
@@ -0,0 +1,194 @@
+// Package openmeteo implements provider.Provider against the free
+// Open-Meteo geocoding and forecast APIs. It's the default provider and the
+// only one that needs no API key.
+package openmeteo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/luxeave/go-weather-forecast/provider"
+)
+
+// Provider is the Open-Meteo backend. Its zero value is ready to use.
+type Provider struct{}
+
+// New returns an Open-Meteo provider.Provider.
+func New() *Provider {
+	return &Provider{}
+}
+
+type geoResponse struct {
+	Results []struct {
+		Name      string  `json:"name"`
+		Latitude  float64 `json:"latitude"`
+		Longitude float64 `json:"longitude"`
+		Admin1    string  `json:"admin1"`
+		Country   string  `json:"country"`
+		Timezone  string  `json:"timezone"`
+	} `json:"results"`
+}
+
+// Geocode asks Open-Meteo's geocoding API for up to count matches,
+// carrying each one's admin1/country/timezone so callers can disambiguate
+// same-named places (e.g. the dozens of "Springfield"s in the US).
+func (p *Provider) Geocode(ctx context.Context, city string, count int) ([]provider.GeocodeCandidate, error) {
+	if count < 1 {
+		count = 1
+	}
+
+	endpoint := fmt.Sprintf("https://geocoding-api.open-meteo.com/v1/search?name=%s&count=%d&language=en&format=json", url.QueryEscape(city), count)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("openmeteo: geocoding request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var geo geoResponse
+	if err := json.NewDecoder(resp.Body).Decode(&geo); err != nil {
+		return nil, fmt.Errorf("openmeteo: decoding geocoding response: %w", err)
+	}
+
+	if len(geo.Results) < 1 {
+		return nil, errors.New("openmeteo: no results found")
+	}
+
+	candidates := make([]provider.GeocodeCandidate, 0, len(geo.Results))
+	for _, r := range geo.Results {
+		candidates = append(candidates, provider.GeocodeCandidate{
+			LatLong:  provider.LatLong{Latitude: r.Latitude, Longitude: r.Longitude},
+			Name:     r.Name,
+			Admin1:   r.Admin1,
+			Country:  r.Country,
+			Timezone: r.Timezone,
+		})
+	}
+
+	return candidates, nil
+}
+
+type forecastResponse struct {
+	Timezone string `json:"timezone"`
+	Hourly   struct {
+		Time          []string  `json:"time"`
+		Temperature2m []float64 `json:"temperature_2m"`
+	} `json:"hourly"`
+	Daily struct {
+		Time             []string  `json:"time"`
+		WeatherCode      []int     `json:"weather_code"`
+		Temperature2mMax []float64 `json:"temperature_2m_max"`
+		Temperature2mMin []float64 `json:"temperature_2m_min"`
+		PrecipitationSum []float64 `json:"precipitation_sum"`
+		Sunrise          []string  `json:"sunrise"`
+		Sunset           []string  `json:"sunset"`
+	} `json:"daily"`
+	CurrentWeather struct {
+		Temperature float64 `json:"temperature"`
+		WeatherCode int     `json:"weathercode"`
+	} `json:"current_weather"`
+}
+
+func (p *Provider) Forecast(ctx context.Context, loc provider.LatLong, opts provider.ForecastOptions) (provider.Forecast, error) {
+	days := opts.Days
+	if days < 1 {
+		days = 1
+	}
+	if days > 16 {
+		days = 16
+	}
+
+	endpoint := fmt.Sprintf(
+		"https://api.open-meteo.com/v1/forecast?latitude=%.6f&longitude=%.6f"+
+			"&hourly=temperature_2m"+
+			"&daily=weather_code,temperature_2m_max,temperature_2m_min,precipitation_sum,sunrise,sunset"+
+			"&current_weather=true"+
+			"&temperature_unit=celsius&forecast_days=%d&timezone=auto",
+		loc.Latitude, loc.Longitude, days,
+	)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return provider.Forecast{}, err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return provider.Forecast{}, fmt.Errorf("openmeteo: forecast request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var raw forecastResponse
+	if err := json.NewDecoder(resp.Body).Decode(&raw); err != nil {
+		return provider.Forecast{}, fmt.Errorf("openmeteo: decoding forecast response: %w", err)
+	}
+
+	loc2, err := time.LoadLocation(raw.Timezone)
+	if err != nil {
+		loc2 = time.UTC
+	}
+
+	forecast := provider.Forecast{
+		Timezone: raw.Timezone,
+		Current: provider.CurrentConditions{
+			TemperatureC: raw.CurrentWeather.Temperature,
+			WeatherCode:  raw.CurrentWeather.WeatherCode,
+		},
+	}
+
+	for i, t := range raw.Hourly.Time {
+		parsed, err := time.ParseInLocation("2006-01-02T15:04", t, loc2)
+		if err != nil {
+			return provider.Forecast{}, err
+		}
+		forecast.Hourly = append(forecast.Hourly, provider.Hour{
+			Time:         parsed,
+			TemperatureC: raw.Hourly.Temperature2m[i],
+		})
+	}
+
+	for i, d := range raw.Daily.Time {
+		date, err := time.ParseInLocation("2006-01-02", d, loc2)
+		if err != nil {
+			return provider.Forecast{}, err
+		}
+
+		day := provider.Day{Date: date}
+		if i < len(raw.Daily.WeatherCode) {
+			day.WeatherCode = raw.Daily.WeatherCode[i]
+		}
+		if i < len(raw.Daily.Temperature2mMax) {
+			day.HighC = raw.Daily.Temperature2mMax[i]
+		}
+		if i < len(raw.Daily.Temperature2mMin) {
+			day.LowC = raw.Daily.Temperature2mMin[i]
+		}
+		if i < len(raw.Daily.PrecipitationSum) {
+			day.PrecipitationMM = raw.Daily.PrecipitationSum[i]
+		}
+		if i < len(raw.Daily.Sunrise) {
+			if sunrise, err := time.ParseInLocation("2006-01-02T15:04", raw.Daily.Sunrise[i], loc2); err == nil {
+				day.Sunrise = sunrise
+			}
+		}
+		if i < len(raw.Daily.Sunset) {
+			if sunset, err := time.ParseInLocation("2006-01-02T15:04", raw.Daily.Sunset[i], loc2); err == nil {
+				day.Sunset = sunset
+			}
+		}
+
+		forecast.Daily = append(forecast.Daily, day)
+	}
+
+	return forecast, nil
+}
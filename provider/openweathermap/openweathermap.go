@@ -0,0 +1,255 @@
+// Package openweathermap implements provider.Provider against the
+// OpenWeatherMap geocoding and 5-day/3-hour forecast APIs, as used by
+// telegraf's openweathermap input and the OpenWeather-gRPC bridge.
+package openweathermap
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/luxeave/go-weather-forecast/provider"
+)
+
+const (
+	geocodeEndpoint  = "https://api.openweathermap.org/geo/1.0/direct"
+	forecastEndpoint = "https://api.openweathermap.org/data/2.5/forecast"
+)
+
+// Provider is the OpenWeatherMap backend. An API key ("appid") is required;
+// sign up at https://openweathermap.org/api.
+type Provider struct {
+	APIKey string
+}
+
+// New returns an OpenWeatherMap provider.Provider authenticated with
+// apiKey.
+func New(apiKey string) *Provider {
+	return &Provider{APIKey: apiKey}
+}
+
+type geoResult struct {
+	Name    string  `json:"name"`
+	Lat     float64 `json:"lat"`
+	Lon     float64 `json:"lon"`
+	State   string  `json:"state"`
+	Country string  `json:"country"`
+}
+
+// Geocode asks OpenWeatherMap's direct geocoding API for up to count
+// matches. OWM has no timezone field on this endpoint, so
+// GeocodeCandidate.Timezone is left blank.
+func (p *Provider) Geocode(ctx context.Context, city string, count int) ([]provider.GeocodeCandidate, error) {
+	if count < 1 {
+		count = 1
+	}
+
+	endpoint := fmt.Sprintf("%s?q=%s&limit=%d&appid=%s", geocodeEndpoint, url.QueryEscape(city), count, url.QueryEscape(p.APIKey))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("openweathermap: geocoding request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var results []geoResult
+	if err := json.NewDecoder(resp.Body).Decode(&results); err != nil {
+		return nil, fmt.Errorf("openweathermap: decoding geocoding response: %w", err)
+	}
+
+	if len(results) < 1 {
+		return nil, errors.New("openweathermap: no results found")
+	}
+
+	candidates := make([]provider.GeocodeCandidate, 0, len(results))
+	for _, r := range results {
+		candidates = append(candidates, provider.GeocodeCandidate{
+			LatLong: provider.LatLong{Latitude: r.Lat, Longitude: r.Lon},
+			Name:    r.Name,
+			Admin1:  r.State,
+			Country: r.Country,
+		})
+	}
+
+	return candidates, nil
+}
+
+type forecastResponse struct {
+	City struct {
+		Timezone int `json:"timezone"` // seconds east of UTC
+	} `json:"city"`
+	List []struct {
+		DtTxt string `json:"dt_txt"`
+		Main  struct {
+			Temp    float64 `json:"temp"`
+			TempMin float64 `json:"temp_min"`
+			TempMax float64 `json:"temp_max"`
+		} `json:"main"`
+		Weather []struct {
+			ID int `json:"id"`
+		} `json:"weather"`
+		Rain map[string]float64 `json:"rain"`
+		Snow map[string]float64 `json:"snow"`
+	} `json:"list"`
+}
+
+func (p *Provider) Forecast(ctx context.Context, loc provider.LatLong, opts provider.ForecastOptions) (provider.Forecast, error) {
+	units := opts.Units
+	if units == "" {
+		units = provider.UnitsMetric
+	}
+	lang := opts.Language
+	if lang == "" {
+		lang = "en"
+	}
+
+	endpoint := fmt.Sprintf("%s?lat=%.6f&lon=%.6f&units=%s&lang=%s&appid=%s",
+		forecastEndpoint, loc.Latitude, loc.Longitude, units, lang, url.QueryEscape(p.APIKey))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return provider.Forecast{}, err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return provider.Forecast{}, fmt.Errorf("openweathermap: forecast request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var raw forecastResponse
+	if err := json.NewDecoder(resp.Body).Decode(&raw); err != nil {
+		return provider.Forecast{}, fmt.Errorf("openweathermap: decoding forecast response: %w", err)
+	}
+
+	tzName := utcOffsetName(raw.City.Timezone)
+	tz := time.FixedZone(tzName, raw.City.Timezone)
+	forecast := provider.Forecast{Timezone: tzName}
+
+	byDay := make(map[string]*provider.Day)
+	var order []string
+
+	for i, entry := range raw.List {
+		t, err := time.ParseInLocation("2006-01-02 15:04:05", entry.DtTxt, tz)
+		if err != nil {
+			return provider.Forecast{}, err
+		}
+
+		tempC := toCelsius(entry.Main.Temp, units)
+		forecast.Hourly = append(forecast.Hourly, provider.Hour{Time: t, TemperatureC: tempC})
+
+		if i == 0 {
+			code := 0
+			if len(entry.Weather) > 0 {
+				code = toWMOCode(entry.Weather[0].ID)
+			}
+			forecast.Current = provider.CurrentConditions{TemperatureC: tempC, WeatherCode: code}
+		}
+
+		high := toCelsius(entry.Main.TempMax, units)
+		low := toCelsius(entry.Main.TempMin, units)
+
+		dayKey := t.Format("2006-01-02")
+		day, ok := byDay[dayKey]
+		if !ok {
+			day = &provider.Day{
+				Date:  time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, tz),
+				HighC: high,
+				LowC:  low,
+			}
+			byDay[dayKey] = day
+			order = append(order, dayKey)
+		}
+
+		if high > day.HighC {
+			day.HighC = high
+		}
+		if low < day.LowC {
+			day.LowC = low
+		}
+		if len(entry.Weather) > 0 {
+			day.WeatherCode = toWMOCode(entry.Weather[0].ID)
+		}
+		day.PrecipitationMM += sumValues(entry.Rain) + sumValues(entry.Snow)
+	}
+
+	for _, key := range order {
+		forecast.Daily = append(forecast.Daily, *byDay[key])
+	}
+
+	return forecast, nil
+}
+
+func sumValues(m map[string]float64) float64 {
+	var total float64
+	for _, v := range m {
+		total += v
+	}
+	return total
+}
+
+// utcOffsetName formats a UTC offset in seconds as "+05:30"/"-04:00".
+// OpenWeatherMap's forecast endpoint only gives a raw offset, unlike
+// Open-Meteo's named IANA zone, so this is the most meaningful name
+// Forecast.Timezone can carry for this backend.
+func utcOffsetName(secondsEastOfUTC int) string {
+	sign := "+"
+	offset := secondsEastOfUTC
+	if offset < 0 {
+		sign = "-"
+		offset = -offset
+	}
+	return fmt.Sprintf("%s%02d:%02d", sign, offset/3600, (offset%3600)/60)
+}
+
+// toCelsius normalizes an OpenWeatherMap temperature (in whatever unit the
+// `units` query parameter requested) back to Celsius.
+func toCelsius(temp float64, units provider.Units) float64 {
+	switch units {
+	case provider.UnitsImperial:
+		return (temp - 32) * 5 / 9
+	case provider.UnitsStandard:
+		return temp - 273.15
+	default:
+		return temp
+	}
+}
+
+// toWMOCode maps an OpenWeatherMap condition code (weather[].id) onto the
+// canonical WMO code space used across providers.
+// https://openweathermap.org/weather-conditions
+func toWMOCode(owmID int) int {
+	switch {
+	case owmID >= 200 && owmID < 300:
+		return 95 // thunderstorm
+	case owmID >= 300 && owmID < 400:
+		return 51 // drizzle
+	case owmID >= 500 && owmID < 520:
+		return 63 // rain
+	case owmID >= 520 && owmID < 600:
+		return 80 // shower rain
+	case owmID >= 600 && owmID < 700:
+		return 71 // snow
+	case owmID >= 700 && owmID < 800:
+		return 45 // fog/mist/haze
+	case owmID == 800:
+		return 0 // clear sky
+	case owmID == 801:
+		return 1 // few clouds
+	case owmID == 802:
+		return 2 // scattered clouds
+	case owmID == 803 || owmID == 804:
+		return 3 // broken/overcast clouds
+	default:
+		return -1 // unknown
+	}
+}
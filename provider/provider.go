@@ -0,0 +1,89 @@
+// Package provider defines the interface weather backends implement, plus
+// the internal, provider-agnostic shapes that the rest of the app (display
+// formatting, templates, the gRPC API) is built against. Concrete backends
+// live in the openmeteo, openweathermap, and wwo subpackages.
+package provider
+
+import (
+	"context"
+	"time"
+)
+
+// Units selects the unit system a caller wants a Forecast rendered in.
+// Forecast values are always normalized to Celsius/mm internally; Units is
+// only meaningful as an upstream request parameter for providers whose API
+// requires picking one (e.g. OpenWeatherMap's `units` query parameter).
+type Units string
+
+const (
+	UnitsMetric   Units = "metric"
+	UnitsImperial Units = "imperial"
+	UnitsStandard Units = "standard"
+)
+
+// LatLong is a resolved geographic coordinate.
+type LatLong struct {
+	Latitude  float64
+	Longitude float64
+}
+
+// GeocodeCandidate is one match for a geocoding query, carrying enough
+// administrative detail (admin1/country) to disambiguate same-named
+// places (e.g. the dozens of "Springfield"s in the US).
+type GeocodeCandidate struct {
+	LatLong
+	Name     string
+	Admin1   string
+	Country  string
+	Timezone string
+}
+
+// ForecastOptions carries the caller's display preferences through to a
+// Provider's Forecast call.
+type ForecastOptions struct {
+	Units    Units
+	Language string
+	Days     int
+}
+
+// CurrentConditions is the normalized "right now" snapshot.
+type CurrentConditions struct {
+	TemperatureC float64
+	WeatherCode  int // canonical WMO code, see Describe
+}
+
+// Hour is one hourly temperature sample.
+type Hour struct {
+	Time         time.Time
+	TemperatureC float64
+}
+
+// Day is one daily-aggregated forecast entry.
+type Day struct {
+	Date            time.Time
+	HighC           float64
+	LowC            float64
+	PrecipitationMM float64
+	WeatherCode     int // canonical WMO code, see Describe
+	Sunrise         time.Time
+	Sunset          time.Time
+}
+
+// Forecast is the provider-agnostic result of a Forecast call: whatever
+// shape the upstream API returned, it ends up here normalized to Celsius,
+// millimeters, and WMO weather codes.
+type Forecast struct {
+	Timezone string
+	Current  CurrentConditions
+	Hourly   []Hour
+	Daily    []Day
+}
+
+// Provider is implemented by each weather backend (Open-Meteo,
+// OpenWeatherMap, WWO, ...). Geocode resolves a city name to up to count
+// candidates, ranked as the backend's own API ranks them; Forecast fetches
+// the weather for a resolved coordinate.
+type Provider interface {
+	Geocode(ctx context.Context, city string, count int) ([]GeocodeCandidate, error)
+	Forecast(ctx context.Context, loc LatLong, opts ForecastOptions) (Forecast, error)
+}
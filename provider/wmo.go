@@ -0,0 +1,61 @@
+package provider
+
+// Condition describes the human-readable meaning of a canonical WMO
+// weather code. https://open-meteo.com/en/docs#weathervariables
+type Condition struct {
+	Description string
+	Icon        string
+}
+
+// wmoCodes maps WMO weather interpretation codes to a short description
+// and an emoji icon, grouped the way glance's openmeteo feed does (clear,
+// cloud, fog, drizzle, rain, snow, thunderstorm). Other providers normalize
+// their own condition codes onto this same space (see openweathermap's
+// toWMOCode) so display code only has to know one table.
+var wmoCodes = map[int]Condition{
+	0: {"Clear sky", "☀️"},
+	1: {"Mainly clear", "🌤️"},
+	2: {"Partly cloudy", "⛅"},
+	3: {"Overcast", "☁️"},
+
+	45: {"Fog", "🌫️"},
+	48: {"Depositing rime fog", "🌫️"},
+
+	51: {"Light drizzle", "🌦️"},
+	53: {"Moderate drizzle", "🌦️"},
+	55: {"Dense drizzle", "🌧️"},
+	56: {"Light freezing drizzle", "🌧️"},
+	57: {"Dense freezing drizzle", "🌧️"},
+
+	61: {"Slight rain", "🌦️"},
+	63: {"Moderate rain", "🌧️"},
+	65: {"Heavy rain", "🌧️"},
+	66: {"Light freezing rain", "🌧️"},
+	67: {"Heavy freezing rain", "🌧️"},
+
+	71: {"Slight snow fall", "🌨️"},
+	73: {"Moderate snow fall", "🌨️"},
+	75: {"Heavy snow fall", "❄️"},
+	77: {"Snow grains", "❄️"},
+
+	80: {"Slight rain showers", "🌦️"},
+	81: {"Moderate rain showers", "🌧️"},
+	82: {"Violent rain showers", "🌧️"},
+
+	85: {"Slight snow showers", "🌨️"},
+	86: {"Heavy snow showers", "🌨️"},
+
+	95: {"Thunderstorm", "⛈️"},
+	96: {"Thunderstorm with slight hail", "⛈️"},
+	99: {"Thunderstorm with heavy hail", "⛈️"},
+}
+
+// Describe returns the description and icon for a canonical WMO weather
+// code, falling back to a generic "unknown" entry for codes we don't
+// recognize.
+func Describe(code int) Condition {
+	if cond, ok := wmoCodes[code]; ok {
+		return cond
+	}
+	return Condition{"Unknown", "❓"}
+}
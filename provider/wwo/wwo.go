@@ -0,0 +1,218 @@
+// Package wwo implements provider.Provider against the World Weather
+// Online ("WWO") API.
+package wwo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/luxeave/go-weather-forecast/provider"
+)
+
+const weatherEndpoint = "https://api.worldweatheronline.com/premium/v1/weather.ashx"
+
+// Provider is the World Weather Online backend, authenticated with an API
+// key passed as the `key` query parameter.
+type Provider struct {
+	APIKey string
+}
+
+// New returns a WWO provider.Provider authenticated with apiKey.
+func New(apiKey string) *Provider {
+	return &Provider{APIKey: apiKey}
+}
+
+type wwoResponse struct {
+	Data struct {
+		NearestArea []struct {
+			Latitude  string `json:"latitude"`
+			Longitude string `json:"longitude"`
+			AreaName  []struct {
+				Value string `json:"value"`
+			} `json:"areaName"`
+			Region []struct {
+				Value string `json:"value"`
+			} `json:"region"`
+			Country []struct {
+				Value string `json:"value"`
+			} `json:"country"`
+		} `json:"nearest_area"`
+		CurrentCondition []struct {
+			TempC       string `json:"temp_C"`
+			WeatherCode string `json:"weatherCode"`
+		} `json:"current_condition"`
+		Weather []struct {
+			Date      string `json:"date"`
+			MaxtempC  string `json:"maxtempC"`
+			MintempC  string `json:"mintempC"`
+			Astronomy []struct {
+				Sunrise string `json:"sunrise"`
+				Sunset  string `json:"sunset"`
+			} `json:"astronomy"`
+			Hourly []struct {
+				Time        string `json:"time"`
+				TempC       string `json:"tempC"`
+				WeatherCode string `json:"weatherCode"`
+			} `json:"hourly"`
+		} `json:"weather"`
+	} `json:"data"`
+}
+
+// Geocode asks WWO for a single day's weather at city and reads back its
+// resolved nearest_area; WWO has no standalone geocoding endpoint, and its
+// weather lookup only ever resolves to the single nearest area, so count
+// is ignored and at most one candidate comes back.
+func (p *Provider) Geocode(ctx context.Context, city string, count int) ([]provider.GeocodeCandidate, error) {
+	endpoint := fmt.Sprintf("%s?key=%s&q=%s&format=json&num_of_days=1",
+		weatherEndpoint, url.QueryEscape(p.APIKey), url.QueryEscape(city))
+
+	raw, err := p.fetch(ctx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(raw.Data.NearestArea) < 1 {
+		return nil, errors.New("wwo: no results found")
+	}
+
+	area := raw.Data.NearestArea[0]
+	lat, err := strconv.ParseFloat(area.Latitude, 64)
+	if err != nil {
+		return nil, fmt.Errorf("wwo: parsing latitude: %w", err)
+	}
+	lon, err := strconv.ParseFloat(area.Longitude, 64)
+	if err != nil {
+		return nil, fmt.Errorf("wwo: parsing longitude: %w", err)
+	}
+
+	candidate := provider.GeocodeCandidate{LatLong: provider.LatLong{Latitude: lat, Longitude: lon}}
+	if len(area.AreaName) > 0 {
+		candidate.Name = area.AreaName[0].Value
+	}
+	if len(area.Region) > 0 {
+		candidate.Admin1 = area.Region[0].Value
+	}
+	if len(area.Country) > 0 {
+		candidate.Country = area.Country[0].Value
+	}
+
+	return []provider.GeocodeCandidate{candidate}, nil
+}
+
+func (p *Provider) Forecast(ctx context.Context, loc provider.LatLong, opts provider.ForecastOptions) (provider.Forecast, error) {
+	days := opts.Days
+	if days < 1 {
+		days = 1
+	}
+	if days > 15 {
+		days = 15 // WWO's premium plan caps forecasts at 15 days
+	}
+
+	endpoint := fmt.Sprintf("%s?key=%s&q=%.6f,%.6f&format=json&num_of_days=%d&tp=1",
+		weatherEndpoint, url.QueryEscape(p.APIKey), loc.Latitude, loc.Longitude, days)
+
+	raw, err := p.fetch(ctx, endpoint)
+	if err != nil {
+		return provider.Forecast{}, err
+	}
+
+	var forecast provider.Forecast
+	if len(raw.Data.CurrentCondition) > 0 {
+		cur := raw.Data.CurrentCondition[0]
+		tempC, _ := strconv.ParseFloat(cur.TempC, 64)
+		code, _ := strconv.Atoi(cur.WeatherCode)
+		forecast.Current = provider.CurrentConditions{TemperatureC: tempC, WeatherCode: toWMOCode(code)}
+	}
+
+	for _, day := range raw.Data.Weather {
+		date, err := time.Parse("2006-01-02", day.Date)
+		if err != nil {
+			return provider.Forecast{}, err
+		}
+
+		high, _ := strconv.ParseFloat(day.MaxtempC, 64)
+		low, _ := strconv.ParseFloat(day.MintempC, 64)
+
+		d := provider.Day{Date: date, HighC: high, LowC: low}
+		if len(day.Hourly) > 0 {
+			if code, err := strconv.Atoi(day.Hourly[0].WeatherCode); err == nil {
+				d.WeatherCode = toWMOCode(code)
+			}
+		}
+		if len(day.Astronomy) > 0 {
+			if sunrise, err := time.Parse("2006-01-02 03:04 PM", day.Date+" "+day.Astronomy[0].Sunrise); err == nil {
+				d.Sunrise = sunrise
+			}
+			if sunset, err := time.Parse("2006-01-02 03:04 PM", day.Date+" "+day.Astronomy[0].Sunset); err == nil {
+				d.Sunset = sunset
+			}
+		}
+		forecast.Daily = append(forecast.Daily, d)
+
+		for _, h := range day.Hourly {
+			minutes, err := strconv.Atoi(h.Time)
+			if err != nil {
+				continue
+			}
+			tempC, _ := strconv.ParseFloat(h.TempC, 64)
+			hourTime := date.Add(time.Duration(minutes/100) * time.Hour)
+			forecast.Hourly = append(forecast.Hourly, provider.Hour{Time: hourTime, TemperatureC: tempC})
+		}
+	}
+
+	return forecast, nil
+}
+
+func (p *Provider) fetch(ctx context.Context, endpoint string) (wwoResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return wwoResponse{}, err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return wwoResponse{}, fmt.Errorf("wwo: request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var raw wwoResponse
+	if err := json.NewDecoder(resp.Body).Decode(&raw); err != nil {
+		return wwoResponse{}, fmt.Errorf("wwo: decoding response: %w", err)
+	}
+
+	return raw, nil
+}
+
+// toWMOCode maps a WWO weather code onto the canonical WMO code space used
+// across providers. WWO's codes are listed at
+// https://www.worldweatheronline.com/weather-api/api/docs/weather-icons.aspx
+func toWMOCode(wwoCode int) int {
+	switch wwoCode {
+	case 113:
+		return 0 // Sunny / Clear
+	case 116:
+		return 2 // Partly cloudy
+	case 119, 122:
+		return 3 // Cloudy / Overcast
+	case 143, 248, 260:
+		return 45 // Mist / Fog
+	case 176, 263, 266, 293, 296:
+		return 51 // Patchy/light rain or drizzle
+	case 299, 302, 305, 308, 356, 359:
+		return 63 // Moderate/heavy rain
+	case 353:
+		return 80 // Light rain shower
+	case 179, 182, 227, 230, 317, 320, 323, 326, 329, 332, 335, 338, 368, 371:
+		return 71 // Snow
+	case 200:
+		return 95 // Thundery outbreaks
+	default:
+		return -1
+	}
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+
+	"github.com/luxeave/go-weather-forecast/cache"
+	"github.com/luxeave/go-weather-forecast/provider"
+)
+
+// geoCache persists geocoding results to disk with an infinite TTL: a
+// city's coordinates essentially never change, so once resolved they're
+// worth keeping across restarts in addition to the `cities` table.
+var geoCache cache.Cache = mustNewGeoCache()
+
+// weatherCache holds short-lived forecast responses in memory, keyed by
+// location/day-count/units.
+var weatherCache cache.Cache = cache.NewLRU(256)
+
+// weatherSF collapses concurrent background refreshes for the same cache
+// key into a single upstream request.
+var weatherSF singleflight.Group
+
+func mustNewGeoCache() cache.Cache {
+	dir := os.Getenv("GEOCODE_CACHE_DIR")
+	if dir == "" {
+		dir = ".cache/geocode"
+	}
+	disk, err := cache.NewDisk(dir)
+	if err != nil {
+		// A cold-start disk failure shouldn't crash the server: fall back
+		// to an in-memory cache with an effectively unbounded capacity.
+		return cache.NewLRU(0)
+	}
+	return disk
+}
+
+// weatherCacheTTL is how long a cached forecast is served as fresh,
+// configurable via WEATHER_CACHE_TTL (e.g. "10m"). Defaults to 10 minutes.
+func weatherCacheTTL() time.Duration {
+	if raw := os.Getenv("WEATHER_CACHE_TTL"); raw != "" {
+		if d, err := time.ParseDuration(raw); err == nil {
+			return d
+		}
+	}
+	return 10 * time.Minute
+}
+
+// weatherCacheMaxStale is how long past weatherCacheTTL a cached forecast
+// may still be served (while a refresh happens in the background) before
+// it's treated as a full miss.
+func weatherCacheMaxStale() time.Duration {
+	if raw := os.Getenv("WEATHER_CACHE_MAX_STALE"); raw != "" {
+		if d, err := time.ParseDuration(raw); err == nil {
+			return d
+		}
+	}
+	return 3 * weatherCacheTTL()
+}
+
+// weatherCacheEntry is the envelope stored in weatherCache so we can tell,
+// on read, whether the body is still fresh or merely within the
+// stale-while-revalidate window.
+type weatherCacheEntry struct {
+	FetchedAt time.Time         `json:"fetched_at"`
+	Forecast  provider.Forecast `json:"forecast"`
+}
+
+func weatherCacheKey(loc provider.LatLong, opts provider.ForecastOptions) string {
+	return fmt.Sprintf("%.6f,%.6f,%d,%s", loc.Latitude, loc.Longitude, opts.Days, opts.Units)
+}
+
+func geoCacheKey(city string) string {
+	return "geocode:" + city
+}
+
+// cachedGeocode wraps a Provider's Geocode with the infinite-TTL geoCache
+// so repeated lookups for the same city, even across processes, skip the
+// upstream geocoding API. It fetches up to 10 candidates so callers can
+// disambiguate by admin1/country.
+func cachedGeocode(ctx context.Context, p provider.Provider, city string) ([]provider.GeocodeCandidate, error) {
+	key := geoCacheKey(city)
+	if raw, ok := geoCache.Get(key); ok {
+		var candidates []provider.GeocodeCandidate
+		if err := json.Unmarshal(raw, &candidates); err == nil {
+			return candidates, nil
+		}
+	}
+
+	candidates, err := p.Geocode(ctx, city, 10)
+	if err != nil {
+		return nil, err
+	}
+
+	if raw, err := json.Marshal(candidates); err == nil {
+		geoCache.Set(key, raw, 0)
+	}
+
+	return candidates, nil
+}
+
+// cachedForecast wraps a Provider's Forecast with a short TTL cache that
+// serves stale data (up to weatherCacheMaxStale) while refreshing in the
+// background, collapsing concurrent refreshes for the same key via
+// weatherSF.
+func cachedForecast(ctx context.Context, p provider.Provider, loc provider.LatLong, opts provider.ForecastOptions) (provider.Forecast, error) {
+	key := weatherCacheKey(loc, opts)
+	ttl := weatherCacheTTL()
+	maxStale := weatherCacheMaxStale()
+
+	if raw, ok := weatherCache.Get(key); ok {
+		var entry weatherCacheEntry
+		if err := json.Unmarshal(raw, &entry); err == nil {
+			age := time.Since(entry.FetchedAt)
+			if age <= ttl {
+				return entry.Forecast, nil
+			}
+			if age <= maxStale {
+				go refreshForecastCache(p, key, loc, opts)
+				return entry.Forecast, nil
+			}
+		}
+	}
+
+	result, err, _ := weatherSF.Do(key, func() (interface{}, error) {
+		return fetchAndCacheForecast(ctx, p, key, loc, opts)
+	})
+	if err != nil {
+		return provider.Forecast{}, err
+	}
+	return result.(provider.Forecast), nil
+}
+
+func refreshForecastCache(p provider.Provider, key string, loc provider.LatLong, opts provider.ForecastOptions) {
+	// The refresh runs detached from the request that triggered it, so it
+	// gets its own context rather than reusing one tied to that request.
+	_, _, _ = weatherSF.Do(key, func() (interface{}, error) {
+		return fetchAndCacheForecast(context.Background(), p, key, loc, opts)
+	})
+}
+
+func fetchAndCacheForecast(ctx context.Context, p provider.Provider, key string, loc provider.LatLong, opts provider.ForecastOptions) (provider.Forecast, error) {
+	forecast, err := p.Forecast(ctx, loc, opts)
+	if err != nil {
+		return provider.Forecast{}, err
+	}
+
+	entry := weatherCacheEntry{FetchedAt: time.Now(), Forecast: forecast}
+	if raw, err := json.Marshal(entry); err == nil {
+		weatherCache.Set(key, raw, weatherCacheMaxStale())
+	}
+
+	return forecast, nil
+}